@@ -2,14 +2,23 @@ package scalers
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"regexp"
 	"strconv"
+	"strings"
+	"sync"
+	"time"
 
+	appsv1 "k8s.io/api/apps/v1"
+	authv1 "k8s.io/api/authorization/v1"
 	"k8s.io/api/autoscaling/v2beta2"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/metrics/pkg/apis/external_metrics"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -19,12 +28,81 @@ import (
 type kubernetesWorkloadScaler struct {
 	metadata   *kubernetesWorkloadMetadata
 	kubeClient client.Client
+	ownerCache *ownerResolutionCache
+}
+
+// ownerCacheTTL bounds how long a resolved ReplicaSet->Deployment owner is trusted
+// before a new Get is issued, to avoid a kubeClient.Get per pod on every polling cycle.
+const ownerCacheTTL = 30 * time.Second
+
+// ownerResolutionCache memoizes the Deployment owner reference of a given ReplicaSet
+type ownerResolutionCache struct {
+	mu      sync.Mutex
+	entries map[types.NamespacedName]ownerCacheEntry
+}
+
+type ownerCacheEntry struct {
+	deploymentRef metav1.OwnerReference
+	expiresAt     time.Time
+}
+
+func newOwnerResolutionCache() *ownerResolutionCache {
+	return &ownerResolutionCache{entries: make(map[types.NamespacedName]ownerCacheEntry)}
+}
+
+func (c *ownerResolutionCache) get(key types.NamespacedName) (metav1.OwnerReference, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[key]
+	if !ok {
+		return metav1.OwnerReference{}, false
+	}
+	if time.Now().After(entry.expiresAt) {
+		delete(c.entries, key)
+		return metav1.OwnerReference{}, false
+	}
+	return entry.deploymentRef, true
+}
+
+func (c *ownerResolutionCache) set(key types.NamespacedName, deploymentRef metav1.OwnerReference) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := time.Now()
+	for k, entry := range c.entries {
+		if now.After(entry.expiresAt) {
+			delete(c.entries, k)
+		}
+	}
+	c.entries[key] = ownerCacheEntry{deploymentRef: deploymentRef, expiresAt: now.Add(ownerCacheTTL)}
 }
 
 const (
 	kubernetesWorkloadMetricType = "External"
 	podSelectorKey               = "podSelector"
+	notPodSelectorKey            = "notPodSelector"
+	fieldSelectorKey             = "fieldSelector"
 	valueKey                     = "value"
+	countModeKey                 = "countMode"
+	metricTypeKey                = "metricType"
+	namespaceKey                 = "namespace"
+	namespacesKey                = "namespaces"
+	ownerKindKey                 = "ownerKind"
+	ownerNameKey                 = "ownerName"
+	ownerAPIVersionKey           = "ownerAPIVersion"
+)
+
+// countMode controls which pods are counted towards the metric value
+type countMode string
+
+const (
+	// countAll counts every pod that isn't Succeeded/Failed/Unknown (default, legacy behavior)
+	countAll countMode = "all"
+	// countReady only counts pods whose PodReady condition is true
+	countReady countMode = "ready"
+	// countRunning only counts pods in the Running phase
+	countRunning countMode = "running"
+	// countReadinessGates only counts pods whose readiness gates and PodReady condition are all true
+	countReadinessGates countMode = "readiness-gates"
 )
 
 var countIgnoresPhases = []corev1.PodPhase{
@@ -34,15 +112,23 @@ var countIgnoresPhases = []corev1.PodPhase{
 }
 
 type kubernetesWorkloadMetadata struct {
-	podSelector labels.Selector
-	namespace   string
-	value       int64
-	scalerIndex int
+	podSelector     labels.Selector
+	notPodSelector  labels.Selector
+	fieldSelector   fields.Selector
+	namespace       string
+	namespaces      []string
+	value           int64
+	countMode       countMode
+	metricType      v2beta2.MetricTargetType
+	ownerKind       string
+	ownerName       *regexp.Regexp
+	ownerAPIVersion string
+	scalerIndex     int
 }
 
 // NewKubernetesWorkloadScaler creates a new kubernetesWorkloadScaler
 func NewKubernetesWorkloadScaler(kubeClient client.Client, config *ScalerConfig) (Scaler, error) {
-	meta, parseErr := parseWorkloadMetadata(config)
+	meta, parseErr := parseWorkloadMetadata(context.TODO(), kubeClient, config)
 	if parseErr != nil {
 		return nil, fmt.Errorf("error parsing kubernetes workload metadata: %s", parseErr)
 	}
@@ -50,25 +136,135 @@ func NewKubernetesWorkloadScaler(kubeClient client.Client, config *ScalerConfig)
 	return &kubernetesWorkloadScaler{
 		metadata:   meta,
 		kubeClient: kubeClient,
+		ownerCache: newOwnerResolutionCache(),
 	}, nil
 }
 
-func parseWorkloadMetadata(config *ScalerConfig) (*kubernetesWorkloadMetadata, error) {
+func parseWorkloadMetadata(ctx context.Context, kubeClient client.Client, config *ScalerConfig) (*kubernetesWorkloadMetadata, error) {
 	meta := &kubernetesWorkloadMetadata{}
 	var err error
 	meta.namespace = config.Namespace
+	var namespacesExplicit bool
+	meta.namespaces, namespacesExplicit, err = parseWorkloadNamespaces(config)
+	if err != nil {
+		return nil, err
+	}
+	if namespacesExplicit {
+		if err := checkNamespacesRBAC(ctx, kubeClient, meta.namespaces); err != nil {
+			return nil, err
+		}
+	}
+
 	meta.podSelector, err = labels.Parse(config.TriggerMetadata[podSelectorKey])
 	if err != nil || meta.podSelector.String() == "" {
 		return nil, fmt.Errorf("invalid pod selector")
 	}
+
+	meta.notPodSelector = labels.Nothing()
+	if notPodSelector, ok := config.TriggerMetadata[notPodSelectorKey]; ok && notPodSelector != "" {
+		meta.notPodSelector, err = labels.Parse(notPodSelector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid notPodSelector: %s", err)
+		}
+	}
+
+	meta.fieldSelector = fields.Everything()
+	if fieldSelector, ok := config.TriggerMetadata[fieldSelectorKey]; ok && fieldSelector != "" {
+		meta.fieldSelector, err = fields.ParseSelector(fieldSelector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid fieldSelector: %s", err)
+		}
+	}
+
 	meta.value, err = strconv.ParseInt(config.TriggerMetadata[valueKey], 10, 64)
 	if err != nil || meta.value == 0 {
 		return nil, fmt.Errorf("value must be an integer greater than 0")
 	}
+
+	meta.countMode = countAll
+	if mode, ok := config.TriggerMetadata[countModeKey]; ok && mode != "" {
+		meta.countMode = countMode(mode)
+		switch meta.countMode {
+		case countAll, countReady, countRunning, countReadinessGates:
+		default:
+			return nil, fmt.Errorf("countMode must be one of 'all', 'ready', 'running', 'readiness-gates'")
+		}
+	}
+
+	meta.metricType = v2beta2.AverageValueMetricType
+	if metricType, ok := config.TriggerMetadata[metricTypeKey]; ok && metricType != "" {
+		meta.metricType = v2beta2.MetricTargetType(metricType)
+		if meta.metricType != v2beta2.AverageValueMetricType && meta.metricType != v2beta2.ValueMetricType {
+			return nil, fmt.Errorf("metricType must be either 'AverageValue' or 'Value'")
+		}
+	}
+
+	if ownerKind, ok := config.TriggerMetadata[ownerKindKey]; ok && ownerKind != "" {
+		meta.ownerKind = ownerKind
+		ownerName, ok := config.TriggerMetadata[ownerNameKey]
+		if !ok || ownerName == "" {
+			return nil, fmt.Errorf("ownerName must be set when ownerKind is set")
+		}
+		meta.ownerName, err = regexp.Compile(ownerName)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ownerName: %s", err)
+		}
+		meta.ownerAPIVersion = config.TriggerMetadata[ownerAPIVersionKey]
+	}
+
 	meta.scalerIndex = config.ScalerIndex
 	return meta, nil
 }
 
+// parseWorkloadNamespaces determines which namespaces to watch for pods. It defaults to
+// the ScaledObject's own namespace, but a single "namespace" or a comma-separated
+// "namespaces" trigger metadata field lets a workload scale on pod counts observed
+// across several namespaces. The second return value reports whether the operator
+// actually set one of those fields, as opposed to hitting the same-namespace fallback.
+func parseWorkloadNamespaces(config *ScalerConfig) ([]string, bool, error) {
+	if namespaces, ok := config.TriggerMetadata[namespacesKey]; ok && namespaces != "" {
+		var result []string
+		for _, ns := range strings.Split(namespaces, ",") {
+			ns = strings.TrimSpace(ns)
+			if ns == "" {
+				return nil, false, fmt.Errorf("namespaces must be a comma-separated list of non-empty namespace names")
+			}
+			result = append(result, ns)
+		}
+		return result, true, nil
+	}
+
+	if namespace, ok := config.TriggerMetadata[namespaceKey]; ok && namespace != "" {
+		return []string{namespace}, true, nil
+	}
+
+	return []string{config.Namespace}, false, nil
+}
+
+// checkNamespacesRBAC verifies that the operator is allowed to list pods in every
+// requested namespace, so a misconfigured cross-namespace trigger fails fast at parse
+// time instead of silently returning zero metrics at scale time.
+func checkNamespacesRBAC(ctx context.Context, kubeClient client.Client, namespaces []string) error {
+	for _, namespace := range namespaces {
+		sar := &authv1.SelfSubjectAccessReview{
+			Spec: authv1.SelfSubjectAccessReviewSpec{
+				ResourceAttributes: &authv1.ResourceAttributes{
+					Namespace: namespace,
+					Verb:      "list",
+					Resource:  "pods",
+				},
+			},
+		}
+		if err := kubeClient.Create(ctx, sar); err != nil {
+			return fmt.Errorf("unable to verify RBAC for namespace %s: %s", namespace, err)
+		}
+		if !sar.Status.Allowed {
+			return fmt.Errorf("missing RBAC permission to list pods in namespace %s", namespace)
+		}
+	}
+	return nil
+}
+
 // IsActive determines if we need to scale from zero
 func (s *kubernetesWorkloadScaler) IsActive(ctx context.Context) (bool, error) {
 	pods, err := s.getMetricValue(ctx)
@@ -93,10 +289,14 @@ func (s *kubernetesWorkloadScaler) GetMetricSpecForScaling(context.Context) []v2
 			Name: GenerateMetricNameWithIndex(s.metadata.scalerIndex, kedautil.NormalizeString(fmt.Sprintf("workload-%s", s.metadata.namespace))),
 		},
 		Target: v2beta2.MetricTarget{
-			Type:         v2beta2.AverageValueMetricType,
-			AverageValue: targetMetricValue,
+			Type: s.metadata.metricType,
 		},
 	}
+	if s.metadata.metricType == v2beta2.ValueMetricType {
+		externalMetric.Target.Value = targetMetricValue
+	} else {
+		externalMetric.Target.AverageValue = targetMetricValue
+	}
 	metricSpec := v2beta2.MetricSpec{External: externalMetric, Type: kubernetesWorkloadMetricType}
 	return []v2beta2.MetricSpec{metricSpec}
 }
@@ -118,10 +318,58 @@ func (s *kubernetesWorkloadScaler) GetMetrics(ctx context.Context, metricName st
 }
 
 func (s *kubernetesWorkloadScaler) getMetricValue(ctx context.Context) (int, error) {
+	fanCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	counts := make([]int, len(s.metadata.namespaces))
+
+	var mu sync.Mutex
+	var firstErr error
+	var firstErrNamespace string
+
+	for i, namespace := range s.metadata.namespaces {
+		wg.Add(1)
+		go func(i int, namespace string) {
+			defer wg.Done()
+			count, err := s.getMetricValueForNamespace(fanCtx, namespace)
+			if err != nil {
+				// Cancelling one namespace's List causes every other in-flight List to
+				// fail with context.Canceled; don't let that mask the genuine error that
+				// triggered the cancellation in the first place.
+				mu.Lock()
+				if firstErr == nil || errors.Is(firstErr, context.Canceled) {
+					firstErr = err
+					firstErrNamespace = namespace
+				}
+				mu.Unlock()
+				cancel()
+				return
+			}
+			counts[i] = count
+		}(i, namespace)
+	}
+
+	wg.Wait()
+
+	if firstErr != nil {
+		return 0, fmt.Errorf("error listing pods in namespace %s: %s", firstErrNamespace, firstErr)
+	}
+
+	total := 0
+	for _, count := range counts {
+		total += count
+	}
+
+	return total, nil
+}
+
+func (s *kubernetesWorkloadScaler) getMetricValueForNamespace(ctx context.Context, namespace string) (int, error) {
 	podList := &corev1.PodList{}
 	listOptions := client.ListOptions{}
 	listOptions.LabelSelector = s.metadata.podSelector
-	listOptions.Namespace = s.metadata.namespace
+	listOptions.FieldSelector = s.metadata.fieldSelector
+	listOptions.Namespace = namespace
 	opts := []client.ListOption{
 		&listOptions,
 	}
@@ -133,17 +381,130 @@ func (s *kubernetesWorkloadScaler) getMetricValue(ctx context.Context) (int, err
 
 	count := 0
 	for _, pod := range podList.Items {
-		count += getCountValue(pod)
+		if s.metadata.notPodSelector.Matches(labels.Set(pod.Labels)) {
+			continue
+		}
+		if s.metadata.ownerKind != "" {
+			owned, err := s.podHasMatchingOwner(ctx, pod)
+			if err != nil {
+				return 0, err
+			}
+			if !owned {
+				continue
+			}
+		}
+		count += getCountValue(pod, s.metadata.countMode)
 	}
 
 	return count, nil
 }
 
-func getCountValue(pod corev1.Pod) int {
+// podHasMatchingOwner reports whether pod is (transitively) owned by a resource matching
+// the configured ownerKind/ownerName/ownerAPIVersion. A Deployment owner is resolved one
+// hop further, from the pod's ReplicaSet, since pods never reference a Deployment directly.
+func (s *kubernetesWorkloadScaler) podHasMatchingOwner(ctx context.Context, pod corev1.Pod) (bool, error) {
+	for _, ref := range pod.OwnerReferences {
+		if s.ownerRefMatches(ref) {
+			return true, nil
+		}
+		if s.metadata.ownerKind == "Deployment" && ref.Kind == "ReplicaSet" {
+			deploymentRef, found, err := s.resolveReplicaSetDeployment(ctx, pod.Namespace, ref.Name)
+			if err != nil {
+				return false, err
+			}
+			if found && s.ownerRefMatches(deploymentRef) {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+func (s *kubernetesWorkloadScaler) ownerRefMatches(ref metav1.OwnerReference) bool {
+	if ref.Kind != s.metadata.ownerKind {
+		return false
+	}
+	if s.metadata.ownerAPIVersion != "" && ref.APIVersion != s.metadata.ownerAPIVersion {
+		return false
+	}
+	return s.metadata.ownerName.MatchString(ref.Name)
+}
+
+// resolveReplicaSetDeployment returns the owner reference of the Deployment owning the
+// given ReplicaSet, using a TTL cache to avoid a kubeClient.Get per pod per polling cycle.
+func (s *kubernetesWorkloadScaler) resolveReplicaSetDeployment(ctx context.Context, namespace, name string) (metav1.OwnerReference, bool, error) {
+	key := types.NamespacedName{Namespace: namespace, Name: name}
+	if deploymentRef, ok := s.ownerCache.get(key); ok {
+		return deploymentRef, deploymentRef.Kind != "", nil
+	}
+
+	rs := &appsv1.ReplicaSet{}
+	if err := s.kubeClient.Get(ctx, key, rs); err != nil {
+		return metav1.OwnerReference{}, false, err
+	}
+
+	var deploymentRef metav1.OwnerReference
+	for _, ref := range rs.OwnerReferences {
+		if ref.Kind == "Deployment" {
+			deploymentRef = ref
+			break
+		}
+	}
+
+	s.ownerCache.set(key, deploymentRef)
+	return deploymentRef, deploymentRef.Kind != "", nil
+}
+
+func getCountValue(pod corev1.Pod, mode countMode) int {
 	for _, ignore := range countIgnoresPhases {
 		if pod.Status.Phase == ignore {
 			return 0
 		}
 	}
+
+	switch mode {
+	case countReady:
+		if !isPodReady(pod) {
+			return 0
+		}
+	case countRunning:
+		if pod.Status.Phase != corev1.PodRunning {
+			return 0
+		}
+	case countReadinessGates:
+		if !isPodReady(pod) || !areReadinessGatesReady(pod) {
+			return 0
+		}
+	case countAll:
+	}
+
 	return 1
 }
+
+// isPodReady returns true if the pod's PodReady condition is true
+func isPodReady(pod corev1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// areReadinessGatesReady returns true if every condition referenced by the pod's
+// readiness gates is present and set to True
+func areReadinessGatesReady(pod corev1.Pod) bool {
+	for _, gate := range pod.Spec.ReadinessGates {
+		ready := false
+		for _, condition := range pod.Status.Conditions {
+			if condition.Type == gate.ConditionType {
+				ready = condition.Status == corev1.ConditionTrue
+				break
+			}
+		}
+		if !ready {
+			return false
+		}
+	}
+	return true
+}
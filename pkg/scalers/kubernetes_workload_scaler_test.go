@@ -0,0 +1,535 @@
+package scalers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	authv1 "k8s.io/api/authorization/v1"
+	"k8s.io/api/autoscaling/v2beta2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// fakeWorkloadClient is a minimal client.Client stub: it only implements what this
+// scaler actually calls (List, Get, Create), delegating anything else to the embedded
+// nil interface so a test fails loudly if the scaler starts relying on more of it.
+type fakeWorkloadClient struct {
+	client.Client
+	listFunc   func(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error
+	getFunc    func(ctx context.Context, key client.ObjectKey, obj client.Object) error
+	createFunc func(ctx context.Context, obj client.Object, opts ...client.CreateOption) error
+}
+
+func (f *fakeWorkloadClient) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	if f.listFunc != nil {
+		return f.listFunc(ctx, list, opts...)
+	}
+	return nil
+}
+
+func (f *fakeWorkloadClient) Get(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+	if f.getFunc != nil {
+		return f.getFunc(ctx, key, obj)
+	}
+	return nil
+}
+
+func (f *fakeWorkloadClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	if f.createFunc != nil {
+		return f.createFunc(ctx, obj, opts...)
+	}
+	if sar, ok := obj.(*authv1.SelfSubjectAccessReview); ok {
+		sar.Status.Allowed = true
+	}
+	return nil
+}
+
+func validWorkloadTriggerMetadata() map[string]string {
+	return map[string]string{
+		podSelectorKey: "app=foo",
+		valueKey:       "1",
+	}
+}
+
+func TestGetCountValue(t *testing.T) {
+	readyCondition := corev1.PodCondition{Type: corev1.PodReady, Status: corev1.ConditionTrue}
+	notReadyCondition := corev1.PodCondition{Type: corev1.PodReady, Status: corev1.ConditionFalse}
+
+	tests := []struct {
+		name     string
+		pod      corev1.Pod
+		mode     countMode
+		expected int
+	}{
+		{
+			name:     "countAll counts a pending pod",
+			pod:      corev1.Pod{Status: corev1.PodStatus{Phase: corev1.PodPending}},
+			mode:     countAll,
+			expected: 1,
+		},
+		{
+			name:     "countAll ignores succeeded pods",
+			pod:      corev1.Pod{Status: corev1.PodStatus{Phase: corev1.PodSucceeded}},
+			mode:     countAll,
+			expected: 0,
+		},
+		{
+			name:     "countAll ignores failed pods",
+			pod:      corev1.Pod{Status: corev1.PodStatus{Phase: corev1.PodFailed}},
+			mode:     countAll,
+			expected: 0,
+		},
+		{
+			name:     "countAll ignores unknown pods",
+			pod:      corev1.Pod{Status: corev1.PodStatus{Phase: corev1.PodUnknown}},
+			mode:     countAll,
+			expected: 0,
+		},
+		{
+			name:     "countAll counts a crash-looping pod",
+			pod:      corev1.Pod{Status: corev1.PodStatus{Phase: corev1.PodRunning, Conditions: []corev1.PodCondition{notReadyCondition}}},
+			mode:     countAll,
+			expected: 1,
+		},
+		{
+			name:     "countReady excludes a pending pod",
+			pod:      corev1.Pod{Status: corev1.PodStatus{Phase: corev1.PodPending}},
+			mode:     countReady,
+			expected: 0,
+		},
+		{
+			name:     "countReady excludes a pod failing its readiness probe",
+			pod:      corev1.Pod{Status: corev1.PodStatus{Phase: corev1.PodRunning, Conditions: []corev1.PodCondition{notReadyCondition}}},
+			mode:     countReady,
+			expected: 0,
+		},
+		{
+			name:     "countReady counts a ready pod",
+			pod:      corev1.Pod{Status: corev1.PodStatus{Phase: corev1.PodRunning, Conditions: []corev1.PodCondition{readyCondition}}},
+			mode:     countReady,
+			expected: 1,
+		},
+		{
+			name:     "countRunning excludes a pending pod",
+			pod:      corev1.Pod{Status: corev1.PodStatus{Phase: corev1.PodPending}},
+			mode:     countRunning,
+			expected: 0,
+		},
+		{
+			name:     "countRunning counts a running pod regardless of readiness",
+			pod:      corev1.Pod{Status: corev1.PodStatus{Phase: corev1.PodRunning, Conditions: []corev1.PodCondition{notReadyCondition}}},
+			mode:     countRunning,
+			expected: 1,
+		},
+		{
+			name: "countReadinessGates excludes a pod with an unsatisfied readiness gate",
+			pod: corev1.Pod{
+				Spec:   corev1.PodSpec{ReadinessGates: []corev1.PodReadinessGate{{ConditionType: "example.com/feature-1"}}},
+				Status: corev1.PodStatus{Phase: corev1.PodRunning, Conditions: []corev1.PodCondition{readyCondition}},
+			},
+			mode:     countReadinessGates,
+			expected: 0,
+		},
+		{
+			name: "countReadinessGates counts a pod with all readiness gates satisfied",
+			pod: corev1.Pod{
+				Spec: corev1.PodSpec{ReadinessGates: []corev1.PodReadinessGate{{ConditionType: "example.com/feature-1"}}},
+				Status: corev1.PodStatus{
+					Phase: corev1.PodRunning,
+					Conditions: []corev1.PodCondition{
+						readyCondition,
+						{Type: "example.com/feature-1", Status: corev1.ConditionTrue},
+					},
+				},
+			},
+			mode:     countReadinessGates,
+			expected: 1,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if count := getCountValue(test.pod, test.mode); count != test.expected {
+				t.Errorf("expected %d, got %d", test.expected, count)
+			}
+		})
+	}
+}
+
+func TestParseWorkloadMetadataNotPodSelectorDefaultsToNothing(t *testing.T) {
+	config := &ScalerConfig{Namespace: "default", TriggerMetadata: validWorkloadTriggerMetadata()}
+
+	meta, err := parseWorkloadMetadata(context.Background(), &fakeWorkloadClient{}, config)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	pod := corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "foo"}}
+	if meta.notPodSelector.Matches(labels.Set(pod.Labels)) {
+		t.Error("notPodSelector should match nothing by default, or every pod would be excluded")
+	}
+}
+
+func TestGetMetricValueExcludesNotPodSelectorMatches(t *testing.T) {
+	config := &ScalerConfig{
+		Namespace: "default",
+		TriggerMetadata: map[string]string{
+			podSelectorKey:    "app=foo",
+			notPodSelectorKey: "tier=canary",
+			valueKey:          "1",
+		},
+	}
+
+	meta, err := parseWorkloadMetadata(context.Background(), &fakeWorkloadClient{}, config)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	s := &kubernetesWorkloadScaler{
+		metadata: meta,
+		kubeClient: &fakeWorkloadClient{
+			listFunc: func(_ context.Context, list client.ObjectList, _ ...client.ListOption) error {
+				podList := list.(*corev1.PodList)
+				podList.Items = []corev1.Pod{
+					{ObjectMeta: metav1.ObjectMeta{Name: "stable", Labels: map[string]string{"tier": "stable"}}},
+					{ObjectMeta: metav1.ObjectMeta{Name: "canary", Labels: map[string]string{"tier": "canary"}}},
+				}
+				return nil
+			},
+		},
+		ownerCache: newOwnerResolutionCache(),
+	}
+
+	count, err := s.getMetricValue(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if count != 1 {
+		t.Errorf("expected notPodSelector to exclude the canary pod, got count %d", count)
+	}
+}
+
+func TestParseWorkloadMetadataInvalidFieldSelector(t *testing.T) {
+	metadata := validWorkloadTriggerMetadata()
+	metadata[fieldSelectorKey] = "==="
+
+	_, err := parseWorkloadMetadata(context.Background(), &fakeWorkloadClient{}, &ScalerConfig{Namespace: "default", TriggerMetadata: metadata})
+	if err == nil {
+		t.Error("expected an error for an invalid fieldSelector")
+	}
+}
+
+func TestParseWorkloadMetadataSkipsRBACCheckWithoutNamespaceOverride(t *testing.T) {
+	fakeClient := &fakeWorkloadClient{
+		createFunc: func(_ context.Context, obj client.Object, _ ...client.CreateOption) error {
+			return fmt.Errorf("SelfSubjectAccessReview should not be created when namespace/namespaces isn't set")
+		},
+	}
+
+	_, err := parseWorkloadMetadata(context.Background(), fakeClient, &ScalerConfig{Namespace: "default", TriggerMetadata: validWorkloadTriggerMetadata()})
+	if err != nil {
+		t.Errorf("expected no RBAC check for the default same-namespace case, got error: %s", err)
+	}
+}
+
+func TestParseWorkloadMetadataChecksRBACWithExplicitNamespace(t *testing.T) {
+	metadata := validWorkloadTriggerMetadata()
+	metadata[namespaceKey] = "other-namespace"
+
+	fakeClient := &fakeWorkloadClient{
+		createFunc: func(_ context.Context, obj client.Object, _ ...client.CreateOption) error {
+			sar := obj.(*authv1.SelfSubjectAccessReview)
+			sar.Status.Allowed = false
+			return nil
+		},
+	}
+
+	_, err := parseWorkloadMetadata(context.Background(), fakeClient, &ScalerConfig{Namespace: "default", TriggerMetadata: metadata})
+	if err == nil {
+		t.Error("expected an error when RBAC denies access to the explicitly requested namespace")
+	}
+}
+
+func TestGetMetricValuePrefersGenuineErrorOverCancellation(t *testing.T) {
+	meta := &kubernetesWorkloadMetadata{
+		podSelector:    labels.Everything(),
+		notPodSelector: labels.Nothing(),
+		fieldSelector:  fields.Everything(),
+		namespaces:     []string{"broken", "slow"},
+		countMode:      countAll,
+	}
+
+	s := &kubernetesWorkloadScaler{
+		metadata: meta,
+		kubeClient: &fakeWorkloadClient{
+			listFunc: func(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+				listOptions := &client.ListOptions{}
+				for _, opt := range opts {
+					opt.ApplyToList(listOptions)
+				}
+				if listOptions.Namespace == "broken" {
+					return fmt.Errorf("forbidden")
+				}
+				// Simulate the "slow" namespace still being in flight when "broken"
+				// triggers cancellation, so it observes ctx.Done() instead of completing.
+				<-ctx.Done()
+				return ctx.Err()
+			},
+		},
+		ownerCache: newOwnerResolutionCache(),
+	}
+
+	_, err := s.getMetricValue(context.Background())
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if !strings.Contains(err.Error(), "forbidden") {
+		t.Errorf("expected the genuine 'forbidden' error to surface instead of a context-canceled error, got: %s", err)
+	}
+}
+
+func TestGetMetricValueCrossNamespaceAggregation(t *testing.T) {
+	meta := &kubernetesWorkloadMetadata{
+		podSelector:    labels.Everything(),
+		notPodSelector: labels.Nothing(),
+		fieldSelector:  fields.Everything(),
+		namespaces:     []string{"team-a", "team-b"},
+		countMode:      countAll,
+	}
+
+	s := &kubernetesWorkloadScaler{
+		metadata: meta,
+		kubeClient: &fakeWorkloadClient{
+			listFunc: func(_ context.Context, list client.ObjectList, opts ...client.ListOption) error {
+				listOptions := &client.ListOptions{}
+				for _, opt := range opts {
+					opt.ApplyToList(listOptions)
+				}
+				podList := list.(*corev1.PodList)
+				switch listOptions.Namespace {
+				case "team-a":
+					podList.Items = []corev1.Pod{{Status: corev1.PodStatus{Phase: corev1.PodRunning}}, {Status: corev1.PodStatus{Phase: corev1.PodRunning}}}
+				case "team-b":
+					podList.Items = []corev1.Pod{{Status: corev1.PodStatus{Phase: corev1.PodRunning}}}
+				}
+				return nil
+			},
+		},
+		ownerCache: newOwnerResolutionCache(),
+	}
+
+	count, err := s.getMetricValue(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if count != 3 {
+		t.Errorf("expected aggregated count of 3 across namespaces, got %d", count)
+	}
+}
+
+func TestGetMetricValueNamespaceErrorDoesNotSilentlyZeroMetric(t *testing.T) {
+	meta := &kubernetesWorkloadMetadata{
+		podSelector:    labels.Everything(),
+		notPodSelector: labels.Nothing(),
+		fieldSelector:  fields.Everything(),
+		namespaces:     []string{"team-a", "team-b"},
+		countMode:      countAll,
+	}
+
+	s := &kubernetesWorkloadScaler{
+		metadata: meta,
+		kubeClient: &fakeWorkloadClient{
+			listFunc: func(_ context.Context, list client.ObjectList, opts ...client.ListOption) error {
+				listOptions := &client.ListOptions{}
+				for _, opt := range opts {
+					opt.ApplyToList(listOptions)
+				}
+				if listOptions.Namespace == "team-b" {
+					return fmt.Errorf("namespace team-b: forbidden")
+				}
+				podList := list.(*corev1.PodList)
+				podList.Items = []corev1.Pod{{Status: corev1.PodStatus{Phase: corev1.PodRunning}}}
+				return nil
+			},
+		},
+		ownerCache: newOwnerResolutionCache(),
+	}
+
+	count, err := s.getMetricValue(context.Background())
+	if err == nil {
+		t.Fatalf("expected an error when one namespace fails to list, got count %d with no error", count)
+	}
+}
+
+func TestGetMetricSpecForScalingMetricType(t *testing.T) {
+	tests := []struct {
+		name         string
+		metricType   string
+		expectedType v2beta2.MetricTargetType
+	}{
+		{name: "defaults to AverageValue", metricType: "", expectedType: v2beta2.AverageValueMetricType},
+		{name: "honors explicit Value", metricType: "Value", expectedType: v2beta2.ValueMetricType},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			metadata := validWorkloadTriggerMetadata()
+			if test.metricType != "" {
+				metadata[metricTypeKey] = test.metricType
+			}
+
+			meta, err := parseWorkloadMetadata(context.Background(), &fakeWorkloadClient{}, &ScalerConfig{Namespace: "default", TriggerMetadata: metadata})
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			s := &kubernetesWorkloadScaler{metadata: meta}
+			spec := s.GetMetricSpecForScaling(context.Background())
+			if spec[0].External.Target.Type != test.expectedType {
+				t.Errorf("expected metric target type %s, got %s", test.expectedType, spec[0].External.Target.Type)
+			}
+		})
+	}
+}
+
+func TestGetMetricValueOwnerKindFiltersDirectOwner(t *testing.T) {
+	metadata := validWorkloadTriggerMetadata()
+	metadata[ownerKindKey] = "Job"
+	metadata[ownerNameKey] = "^worker-.*"
+
+	meta, err := parseWorkloadMetadata(context.Background(), &fakeWorkloadClient{}, &ScalerConfig{Namespace: "default", TriggerMetadata: metadata})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	s := &kubernetesWorkloadScaler{
+		metadata: meta,
+		kubeClient: &fakeWorkloadClient{
+			listFunc: func(_ context.Context, list client.ObjectList, _ ...client.ListOption) error {
+				podList := list.(*corev1.PodList)
+				podList.Items = []corev1.Pod{
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:            "worker-1-abc",
+							OwnerReferences: []metav1.OwnerReference{{Kind: "Job", Name: "worker-1"}},
+						},
+						Status: corev1.PodStatus{Phase: corev1.PodRunning},
+					},
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:            "other-job-abc",
+							OwnerReferences: []metav1.OwnerReference{{Kind: "Job", Name: "other-job"}},
+						},
+						Status: corev1.PodStatus{Phase: corev1.PodRunning},
+					},
+				}
+				return nil
+			},
+		},
+		ownerCache: newOwnerResolutionCache(),
+	}
+
+	count, err := s.getMetricValue(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if count != 1 {
+		t.Errorf("expected only the worker-1 Job's pod to be counted, got %d", count)
+	}
+}
+
+func TestGetMetricValueOwnerKindResolvesTransitiveDeployment(t *testing.T) {
+	metadata := validWorkloadTriggerMetadata()
+	metadata[ownerKindKey] = "Deployment"
+	metadata[ownerNameKey] = "api"
+	metadata[ownerAPIVersionKey] = "apps/v1"
+
+	meta, err := parseWorkloadMetadata(context.Background(), &fakeWorkloadClient{}, &ScalerConfig{Namespace: "default", TriggerMetadata: metadata})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	pod := corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "api-6d9f-xyz",
+			Namespace:       "default",
+			OwnerReferences: []metav1.OwnerReference{{Kind: "ReplicaSet", Name: "api-6d9f"}},
+		},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+
+	s := &kubernetesWorkloadScaler{
+		metadata: meta,
+		kubeClient: &fakeWorkloadClient{
+			listFunc: func(_ context.Context, list client.ObjectList, _ ...client.ListOption) error {
+				list.(*corev1.PodList).Items = []corev1.Pod{pod}
+				return nil
+			},
+			getFunc: func(_ context.Context, key client.ObjectKey, obj client.Object) error {
+				rs := obj.(*appsv1.ReplicaSet)
+				rs.OwnerReferences = []metav1.OwnerReference{{Kind: "Deployment", Name: "api", APIVersion: "apps/v1"}}
+				return nil
+			},
+		},
+		ownerCache: newOwnerResolutionCache(),
+	}
+
+	count, err := s.getMetricValue(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if count != 1 {
+		t.Errorf("expected the pod owned via ReplicaSet->Deployment to be counted, got %d", count)
+	}
+}
+
+func TestGetMetricValueOwnerAPIVersionMismatchExcludesTransitiveDeployment(t *testing.T) {
+	metadata := validWorkloadTriggerMetadata()
+	metadata[ownerKindKey] = "Deployment"
+	metadata[ownerNameKey] = "api"
+	metadata[ownerAPIVersionKey] = "apps/v2beta1"
+
+	meta, err := parseWorkloadMetadata(context.Background(), &fakeWorkloadClient{}, &ScalerConfig{Namespace: "default", TriggerMetadata: metadata})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	pod := corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "api-6d9f-xyz",
+			Namespace:       "default",
+			OwnerReferences: []metav1.OwnerReference{{Kind: "ReplicaSet", Name: "api-6d9f"}},
+		},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+
+	s := &kubernetesWorkloadScaler{
+		metadata: meta,
+		kubeClient: &fakeWorkloadClient{
+			listFunc: func(_ context.Context, list client.ObjectList, _ ...client.ListOption) error {
+				list.(*corev1.PodList).Items = []corev1.Pod{pod}
+				return nil
+			},
+			getFunc: func(_ context.Context, key client.ObjectKey, obj client.Object) error {
+				rs := obj.(*appsv1.ReplicaSet)
+				rs.OwnerReferences = []metav1.OwnerReference{{Kind: "Deployment", Name: "api", APIVersion: "apps/v1"}}
+				return nil
+			},
+		},
+		ownerCache: newOwnerResolutionCache(),
+	}
+
+	count, err := s.getMetricValue(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if count != 0 {
+		t.Errorf("expected ownerAPIVersion mismatch to exclude the pod, got %d", count)
+	}
+}